@@ -0,0 +1,171 @@
+// Copyright 2015 Google Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package semantic
+
+import "github.com/google/badwolf/triple/predicate"
+
+// StorageStats exposes the cardinality estimates a storage backend can
+// cheaply provide about the triples it holds. OptimizedGraphPatternClauses
+// uses it to cost graph clauses before deciding a join order; a backend
+// that cannot provide real numbers can still satisfy this interface with
+// rough estimates.
+type StorageStats interface {
+	// TriplesForPredicate estimates how many triples exist for p, ignoring
+	// subject and object bindings.
+	TriplesForPredicate(p *predicate.Predicate) int64
+	// SubjectsForPredicate estimates how many distinct subjects appear with p.
+	SubjectsForPredicate(p *predicate.Predicate) int64
+	// ObjectsForPredicate estimates how many distinct objects appear with p.
+	ObjectsForPredicate(p *predicate.Predicate) int64
+}
+
+// unboundCardinality is used to cost a clause whose predicate is itself a
+// binding, since no StorageStats lookup is possible for it.
+const unboundCardinality = int64(1) << 32
+
+// estimatedCardinality returns how many triples c is expected to match,
+// given stats. Clauses that bind S, P and O are the cheapest since they are
+// effectively point lookups; clauses with an unbound predicate binding are
+// the most expensive since no per-predicate statistic can be consulted.
+func estimatedCardinality(c *GraphClause, stats StorageStats) int64 {
+	if c.P == nil {
+		return unboundCardinality
+	}
+	card := stats.TriplesForPredicate(c.P)
+	if card <= 0 {
+		card = 1
+	}
+	if c.S != nil {
+		if s := stats.SubjectsForPredicate(c.P); s > 0 {
+			card /= s
+			if card < 1 {
+				card = 1
+			}
+		}
+	}
+	if c.O != nil {
+		if o := stats.ObjectsForPredicate(c.P); o > 0 {
+			card /= o
+			if card < 1 {
+				card = 1
+			}
+		}
+	}
+	return card
+}
+
+// clauseVariables returns the set of binding names a clause introduces,
+// which is how OptimizedGraphPatternClauses recognizes clauses that share a
+// join variable.
+func clauseVariables(c *GraphClause) map[string]bool {
+	vs := make(map[string]bool)
+	add := func(b string) {
+		if b != "" {
+			vs[b] = true
+		}
+	}
+	add(c.SBinding)
+	add(c.SAlias)
+	add(c.STypeAlias)
+	add(c.SIDAlias)
+	add(c.PAlias)
+	add(c.PAnchorBinding)
+	add(c.PBinding)
+	add(c.PLowerBoundAlias)
+	add(c.PUpperBoundAlias)
+	add(c.PIDAlias)
+	add(c.PAnchorAlias)
+	add(c.OBinding)
+	add(c.OID)
+	add(c.OAlias)
+	add(c.OTypeAlias)
+	add(c.OIDAlias)
+	add(c.OAnchorAlias)
+	add(c.OAnchorBinding)
+	add(c.OLowerBoundAlias)
+	add(c.OUpperBoundAlias)
+	return vs
+}
+
+// sharedVariables counts how many of vs are already present in bound.
+func sharedVariables(vs map[string]bool, bound map[string]bool) int {
+	shared := 0
+	for v := range vs {
+		if bound[v] {
+			shared++
+		}
+	}
+	return shared
+}
+
+// joinDiscount and disconnectedPenalty tune how strongly
+// OptimizedGraphPatternClauses prefers clauses that connect to what has
+// already been placed over cheaper but disconnected ones; a connected
+// clause can be joined in place, while a disconnected one forces a cross
+// product.
+const (
+	joinDiscount        = 10
+	disconnectedPenalty = 10
+)
+
+// OptimizedGraphPatternClauses returns the graph pattern clauses reordered
+// by a greedy, cost-based join ordering: the lowest cardinality clause is
+// placed first, and every following clause is the unplaced one with the
+// lowest score, where a clause's score is its estimated cardinality divided
+// by joinDiscount times the number of bindings it shares with what has
+// already been placed, or multiplied by disconnectedPenalty if it shares
+// none. A cheap clause sharing few bindings can therefore be placed ahead of
+// a costly one sharing more. If stats is nil, it falls back to
+// SortedGraphPatternClauses.
+func (s *Statement) OptimizedGraphPatternClauses(stats StorageStats) []*GraphClause {
+	if stats == nil {
+		return s.SortedGraphPatternClauses()
+	}
+	remaining := append([]*GraphClause{}, s.pattern...)
+	costs := make(map[*GraphClause]int64, len(remaining))
+	vars := make(map[*GraphClause]map[string]bool, len(remaining))
+	for _, c := range remaining {
+		costs[c] = estimatedCardinality(c, stats)
+		vars[c] = clauseVariables(c)
+	}
+
+	ordered := make([]*GraphClause, 0, len(remaining))
+	bound := make(map[string]bool)
+	for len(remaining) > 0 {
+		bestIdx := -1
+		var bestScore int64
+		for i, c := range remaining {
+			score := costs[c]
+			if shared := sharedVariables(vars[c], bound); shared > 0 {
+				score /= int64(shared) * joinDiscount
+				if score < 1 {
+					score = 1
+				}
+			} else if len(bound) > 0 {
+				score *= disconnectedPenalty
+			}
+			if bestIdx == -1 || score < bestScore {
+				bestIdx, bestScore = i, score
+			}
+		}
+		c := remaining[bestIdx]
+		ordered = append(ordered, c)
+		for v := range vars[c] {
+			bound[v] = true
+		}
+		remaining = append(remaining[:bestIdx], remaining[bestIdx+1:]...)
+	}
+	return ordered
+}
@@ -0,0 +1,121 @@
+// Copyright 2015 Google Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package semantic
+
+import (
+	"testing"
+
+	"github.com/google/badwolf/triple/predicate"
+)
+
+// fakeStats is a StorageStats backed by a map keyed by predicate ID, which
+// is all these tests need to exercise the cost-based ordering.
+type fakeStats struct {
+	triples  map[string]int64
+	subjects map[string]int64
+	objects  map[string]int64
+}
+
+func (f fakeStats) TriplesForPredicate(p *predicate.Predicate) int64 {
+	return f.triples[string(p.ID())]
+}
+
+func (f fakeStats) SubjectsForPredicate(p *predicate.Predicate) int64 {
+	return f.subjects[string(p.ID())]
+}
+
+func (f fakeStats) ObjectsForPredicate(p *predicate.Predicate) int64 {
+	return f.objects[string(p.ID())]
+}
+
+func mustPredicate(t *testing.T, id string) *predicate.Predicate {
+	p, err := predicate.NewImmutable(id)
+	if err != nil {
+		t.Fatalf("predicate.NewImmutable(%q) failed with error %v", id, err)
+	}
+	return p
+}
+
+func TestOptimizedGraphPatternClausesNilStatsFallsBackToSpecificity(t *testing.T) {
+	s := &Statement{}
+	s.pattern = []*GraphClause{
+		{SBinding: "?s"},
+		{S: nil, P: mustPredicate(t, "p"), O: nil},
+	}
+	got := s.OptimizedGraphPatternClauses(nil)
+	want := s.SortedGraphPatternClauses()
+	if len(got) != len(want) {
+		t.Fatalf("got %d clauses, want %d", len(got), len(want))
+	}
+	for i := range got {
+		if got[i] != want[i] {
+			t.Errorf("clause %d = %v, want %v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestOptimizedGraphPatternClausesOrdersByCardinality(t *testing.T) {
+	cheap := mustPredicate(t, "cheap")
+	expensive := mustPredicate(t, "expensive")
+	stats := fakeStats{
+		triples: map[string]int64{
+			"cheap":     10,
+			"expensive": 1000000,
+		},
+	}
+	clauseCheap := &GraphClause{P: cheap, OBinding: "?o"}
+	clauseExpensive := &GraphClause{P: expensive, OBinding: "?o2"}
+
+	s := &Statement{}
+	s.pattern = []*GraphClause{clauseExpensive, clauseCheap}
+
+	got := s.OptimizedGraphPatternClauses(stats)
+	if len(got) != 2 {
+		t.Fatalf("got %d clauses, want 2", len(got))
+	}
+	if got[0] != clauseCheap {
+		t.Errorf("expected the lowest cardinality clause first, got %v first", got[0])
+	}
+}
+
+func TestOptimizedGraphPatternClausesPrefersConnectedClause(t *testing.T) {
+	root := mustPredicate(t, "root")
+	connected := mustPredicate(t, "connected")
+	disconnected := mustPredicate(t, "disconnected")
+	stats := fakeStats{
+		triples: map[string]int64{
+			"root":         10,
+			"connected":    50,
+			"disconnected": 20,
+		},
+	}
+	clauseRoot := &GraphClause{P: root, OBinding: "?x"}
+	clauseConnected := &GraphClause{P: connected, SBinding: "?x", OBinding: "?y"}
+	clauseDisconnected := &GraphClause{P: disconnected, SBinding: "?z"}
+
+	s := &Statement{}
+	s.pattern = []*GraphClause{clauseDisconnected, clauseConnected, clauseRoot}
+
+	got := s.OptimizedGraphPatternClauses(stats)
+	if len(got) != 3 {
+		t.Fatalf("got %d clauses, want 3", len(got))
+	}
+	if got[0] != clauseRoot {
+		t.Fatalf("expected clauseRoot first (lowest cardinality), got %v", got[0])
+	}
+	if got[1] != clauseConnected {
+		t.Errorf("expected clauseConnected second because it shares ?x with the placed clause, got %v", got[1])
+	}
+}
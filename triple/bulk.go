@@ -0,0 +1,246 @@
+// Copyright 2015 Google Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package triple
+
+import (
+	"bufio"
+	"compress/bzip2"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/google/badwolf/triple/literal"
+)
+
+// ParsedTriple pairs a successfully parsed triple with the 1-based line
+// number it came from, so BulkParse can report ordering and progress
+// without forcing callers to track it themselves.
+type ParsedTriple struct {
+	Line   int
+	Triple *Triple
+}
+
+// ParseError reports a line that BulkParse could not turn into a triple.
+// Unlike ParseTriple, a single malformed line does not abort the whole load.
+type ParseError struct {
+	Line int
+	Text string
+	Err  error
+}
+
+// Error implements the error interface.
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("triple.BulkParse: line %d: %v (%q)", e.Line, e.Err, e.Text)
+}
+
+// BulkOptions configures BulkParse.
+type BulkOptions struct {
+	// Workers is the number of goroutines used to parse lines concurrently.
+	// A value less than 1 is treated as 1.
+	Workers int
+	// KeepOrder makes BulkParse deliver parsed triples in the same order as
+	// the input, at the cost of buffering ahead-of-order results until the
+	// line they trail behind is ready. When false, triples are delivered in
+	// whatever order workers finish them.
+	KeepOrder bool
+	// Progress, when non-nil, is called after every buffered read of the
+	// input (not once per line) with the cumulative number of bytes read so
+	// far. For gzip or bzip2 input these are decompressed bytes.
+	Progress func(bytesRead int64)
+}
+
+// countingReader wraps an io.Reader and reports every byte read to onRead.
+type countingReader struct {
+	r      io.Reader
+	n      int64
+	onRead func(int64)
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	if c.onRead != nil {
+		c.onRead(c.n)
+	}
+	return n, err
+}
+
+// decompress wraps r with a gzip or bzip2 reader if its magic bytes match
+// one of those formats, otherwise it returns r unchanged.
+func decompress(r io.Reader) (io.Reader, error) {
+	br := bufio.NewReader(r)
+	magic, err := br.Peek(2)
+	if err != nil {
+		// Fewer than 2 bytes available; nothing to sniff, let line scanning
+		// surface the real error (e.g. empty input is not an error).
+		return br, nil
+	}
+	switch {
+	case magic[0] == 0x1f && magic[1] == 0x8b:
+		gz, err := gzip.NewReader(br)
+		if err != nil {
+			return nil, fmt.Errorf("triple.BulkParse: failed to open gzip stream with error %v", err)
+		}
+		return gz, nil
+	case magic[0] == 'B' && magic[1] == 'Z':
+		return bzip2.NewReader(br), nil
+	default:
+		return br, nil
+	}
+}
+
+// BulkParse reads a large triple file from r, one triple per line in the
+// format accepted by ParseTriple, and parses it across opts.Workers
+// goroutines. It returns immediately with a channel of successfully parsed
+// triples and a channel of per-line errors; both are closed once r is
+// exhausted and every in-flight line has been parsed. A malformed line is
+// reported on the error channel and does not stop the load.
+func BulkParse(r io.Reader, b literal.Builder, opts BulkOptions) (<-chan ParsedTriple, <-chan error) {
+	workers := opts.Workers
+	if workers < 1 {
+		workers = 1
+	}
+	out := make(chan ParsedTriple, workers)
+	errs := make(chan error, workers)
+
+	go func() {
+		defer close(out)
+		defer close(errs)
+
+		dr, err := decompress(r)
+		if err != nil {
+			errs <- err
+			return
+		}
+		if opts.Progress != nil {
+			dr = &countingReader{r: dr, onRead: opts.Progress}
+		}
+
+		type job struct {
+			line int
+			text string
+		}
+		jobs := make(chan job, workers)
+		type result struct {
+			line int
+			t    *Triple
+			err  error
+			skip bool
+		}
+		results := make(chan result, workers)
+
+		done := make(chan struct{})
+		for i := 0; i < workers; i++ {
+			go func() {
+				for j := range jobs {
+					t, err := ParseTriple(j.text, b)
+					if err != nil {
+						results <- result{line: j.line, err: &ParseError{Line: j.line, Text: j.text, Err: err}}
+						continue
+					}
+					results <- result{line: j.line, t: t}
+				}
+				done <- struct{}{}
+			}()
+		}
+
+		go func() {
+			s := bufio.NewScanner(dr)
+			s.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+			n := 0
+			for s.Scan() {
+				n++
+				text := strings.TrimSpace(s.Text())
+				if text == "" || strings.HasPrefix(text, "#") {
+					// Record the skip so KeepOrder can advance past this
+					// line number instead of stalling on it forever.
+					results <- result{line: n, skip: true}
+					continue
+				}
+				jobs <- job{line: n, text: text}
+			}
+			if err := s.Err(); err != nil {
+				results <- result{err: fmt.Errorf("triple.BulkParse: failed scanning input with error %v", err)}
+			}
+			close(jobs)
+		}()
+
+		go func() {
+			for i := 0; i < workers; i++ {
+				<-done
+			}
+			close(results)
+		}()
+
+		if !opts.KeepOrder {
+			for res := range results {
+				if res.skip {
+					continue
+				}
+				deliver(res.line, res.t, res.err, out, errs)
+			}
+			return
+		}
+
+		pending := make(map[int]result)
+		next := 1
+		for res := range results {
+			if res.line == 0 {
+				// A scanning error carries no line number; surface it in
+				// place rather than waiting for a line that will never come.
+				deliver(res.line, res.t, res.err, out, errs)
+				continue
+			}
+			pending[res.line] = res
+			for {
+				r, ok := pending[next]
+				if !ok {
+					break
+				}
+				delete(pending, next)
+				if !r.skip {
+					deliver(r.line, r.t, r.err, out, errs)
+				}
+				next++
+			}
+		}
+		// Every line, skipped or not, sends exactly one result, so pending
+		// is empty here once results is closed; anything still left in it
+		// is flushed in line order.
+		lines := make([]int, 0, len(pending))
+		for l := range pending {
+			lines = append(lines, l)
+		}
+		sort.Ints(lines)
+		for _, l := range lines {
+			r := pending[l]
+			if !r.skip {
+				deliver(r.line, r.t, r.err, out, errs)
+			}
+		}
+	}()
+
+	return out, errs
+}
+
+func deliver(line int, t *Triple, err error, out chan<- ParsedTriple, errs chan<- error) {
+	if err != nil {
+		errs <- err
+		return
+	}
+	out <- ParsedTriple{Line: line, Triple: t}
+}
@@ -0,0 +1,113 @@
+// Copyright 2015 Google Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package triple
+
+import (
+	"bytes"
+	"compress/gzip"
+	"strings"
+	"testing"
+
+	"github.com/google/badwolf/triple/literal"
+)
+
+func drainBulkParse(out <-chan ParsedTriple, errs <-chan error) ([]ParsedTriple, []error) {
+	var ts []ParsedTriple
+	var es []error
+	for out != nil || errs != nil {
+		select {
+		case p, ok := <-out:
+			if !ok {
+				out = nil
+				continue
+			}
+			ts = append(ts, p)
+		case e, ok := <-errs:
+			if !ok {
+				errs = nil
+				continue
+			}
+			es = append(es, e)
+		}
+	}
+	return ts, es
+}
+
+func TestBulkParseKeepOrder(t *testing.T) {
+	input := strings.Join([]string{
+		`/person<alice>  "knows"@[]  /person<bob>`,
+		"",
+		"# a comment",
+		`/person<bob>  "knows"@[]  /person<carol>`,
+		`/person<carol>  "knows"@[]  /person<dave>`,
+	}, "\n")
+
+	out, errs := BulkParse(strings.NewReader(input), literal.DefaultBuilder(), BulkOptions{Workers: 4, KeepOrder: true})
+	ts, es := drainBulkParse(out, errs)
+	if len(es) != 0 {
+		t.Fatalf("unexpected errors: %v", es)
+	}
+	if len(ts) != 3 {
+		t.Fatalf("got %d triples, want 3", len(ts))
+	}
+	wantLines := []int{1, 4, 5}
+	for i, p := range ts {
+		if p.Line != wantLines[i] {
+			t.Errorf("triple %d came from line %d, want %d (order not preserved)", i, p.Line, wantLines[i])
+		}
+	}
+}
+
+func TestBulkParseReportsLineErrorsWithoutAborting(t *testing.T) {
+	input := strings.Join([]string{
+		`/person<alice>  "knows"@[]  /person<bob>`,
+		"this is not a valid triple",
+		`/person<bob>  "knows"@[]  /person<carol>`,
+	}, "\n")
+
+	out, errs := BulkParse(strings.NewReader(input), literal.DefaultBuilder(), BulkOptions{Workers: 2, KeepOrder: true})
+	ts, es := drainBulkParse(out, errs)
+	if len(ts) != 2 {
+		t.Fatalf("got %d triples, want 2", len(ts))
+	}
+	if len(es) != 1 {
+		t.Fatalf("got %d errors, want 1", len(es))
+	}
+	pe, ok := es[0].(*ParseError)
+	if !ok {
+		t.Fatalf("error is %T, want *ParseError", es[0])
+	}
+	if pe.Line != 2 {
+		t.Errorf("ParseError.Line = %d, want 2", pe.Line)
+	}
+}
+
+func TestBulkParseGzipAutoDetection(t *testing.T) {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	gz.Write([]byte(`/person<alice>  "knows"@[]  /person<bob>` + "\n"))
+	if err := gz.Close(); err != nil {
+		t.Fatalf("gzip.Close failed with error %v", err)
+	}
+
+	out, errs := BulkParse(&buf, literal.DefaultBuilder(), BulkOptions{Workers: 1})
+	ts, es := drainBulkParse(out, errs)
+	if len(es) != 0 {
+		t.Fatalf("unexpected errors: %v", es)
+	}
+	if len(ts) != 1 {
+		t.Fatalf("got %d triples, want 1", len(ts))
+	}
+}
@@ -0,0 +1,383 @@
+// Copyright 2015 Google Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package codec
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/badwolf/triple"
+	"github.com/google/badwolf/triple/literal"
+	"github.com/google/badwolf/triple/node"
+	"github.com/google/badwolf/triple/predicate"
+)
+
+func init() {
+	Register(binaryCodec{})
+}
+
+// Tags identifying the shape of an encoded object and, when it is a
+// literal, the shape of its value.
+const (
+	objNode = byte(iota)
+	objPredicate
+	objLiteral
+)
+
+const (
+	litBool = byte(iota)
+	litInt64
+	litFloat64
+	litText
+	litBlob
+)
+
+// predImmutable and predTemporal flag which predicate constructor
+// (NewImmutable or NewTemporal) a decoded predicate needs.
+const (
+	predImmutable = byte(iota)
+	predTemporal
+)
+
+// binaryCodec is a compact on-disk/inter-process format: every triple is a
+// sequence of varint-length-prefixed strings and, for literals, a typed tag
+// byte followed by a fixed-width or length-prefixed binary value (bools as
+// one byte, int64 as a zigzag varint, float64 as 8 big-endian bytes). Unlike
+// bwfCodec it never writes decimal ASCII or a "^^type:" tag on the wire,
+// which keeps large snapshots smaller than with the bwf/N-Triples/Turtle
+// text codecs. Decoding still rebuilds each literal by formatting its value
+// as text and passing it to the literal.Builder.
+type binaryCodec struct{}
+
+// Name implements Codec.
+func (binaryCodec) Name() string { return "binary" }
+
+// MediaType implements Codec.
+func (binaryCodec) MediaType() string { return "application/vnd.badwolf.binary" }
+
+// Decode implements Codec.
+func (binaryCodec) Decode(r io.Reader, b literal.Builder) TripleIterator {
+	return &binaryIterator{r: bufio.NewReader(r), b: b}
+}
+
+// Encode implements Codec.
+func (binaryCodec) Encode(w io.Writer, it TripleIterator) error {
+	bw := bufio.NewWriter(w)
+	for {
+		t, err := it.Next()
+		if err == io.EOF {
+			return bw.Flush()
+		}
+		if err != nil {
+			return err
+		}
+		if err := writeNode(bw, t.S()); err != nil {
+			return err
+		}
+		if err := writePredicate(bw, t.P()); err != nil {
+			return err
+		}
+		if err := writeObject(bw, t.O()); err != nil {
+			return err
+		}
+	}
+}
+
+func writeVarint(w io.Writer, v uint64) error {
+	var buf [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(buf[:], v)
+	_, err := w.Write(buf[:n])
+	return err
+}
+
+func writeString(w io.Writer, s string) error {
+	if err := writeVarint(w, uint64(len(s))); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, s)
+	return err
+}
+
+func readString(r *bufio.Reader) (string, error) {
+	l, err := binary.ReadUvarint(r)
+	if err != nil {
+		return "", err
+	}
+	data := make([]byte, l)
+	if _, err := io.ReadFull(r, data); err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// nodeParts splits a node's "/type<id>" string form into its type and id.
+func nodeParts(n *node.Node) (string, string, error) {
+	s := n.String()
+	open := strings.IndexByte(s, '<')
+	if len(s) < 2 || s[0] != '/' || s[len(s)-1] != '>' || open < 0 {
+		return "", "", fmt.Errorf("codec: binary: cannot encode node %q", s)
+	}
+	return s[1:open], s[open+1 : len(s)-1], nil
+}
+
+func writeNode(w io.Writer, n *node.Node) error {
+	typ, id, err := nodeParts(n)
+	if err != nil {
+		return err
+	}
+	if err := writeString(w, typ); err != nil {
+		return err
+	}
+	return writeString(w, id)
+}
+
+func readNode(r *bufio.Reader) (*node.Node, error) {
+	typ, err := readString(r)
+	if err != nil {
+		return nil, err
+	}
+	id, err := readString(r)
+	if err != nil {
+		return nil, err
+	}
+	return node.Parse(fmt.Sprintf("/%s<%s>", typ, id))
+}
+
+func writePredicate(w io.Writer, p *predicate.Predicate) error {
+	if p.Type() == predicate.Temporal {
+		ta, err := p.TimeAnchor()
+		if err != nil {
+			return err
+		}
+		if _, err := w.Write([]byte{predTemporal}); err != nil {
+			return err
+		}
+		if err := writeString(w, string(p.ID())); err != nil {
+			return err
+		}
+		var buf [binary.MaxVarintLen64]byte
+		n := binary.PutVarint(buf[:], ta.UnixNano())
+		_, err = w.Write(buf[:n])
+		return err
+	}
+	if _, err := w.Write([]byte{predImmutable}); err != nil {
+		return err
+	}
+	return writeString(w, string(p.ID()))
+}
+
+func readPredicate(r *bufio.Reader) (*predicate.Predicate, error) {
+	kind, err := r.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+	id, err := readString(r)
+	if err != nil {
+		return nil, err
+	}
+	if kind == predImmutable {
+		return predicate.NewImmutable(id)
+	}
+	nanos, err := binary.ReadVarint(r)
+	if err != nil {
+		return nil, err
+	}
+	return predicate.NewTemporal(id, time.Unix(0, nanos))
+}
+
+func writeObject(w io.Writer, o *triple.Object) error {
+	if n, err := o.Node(); err == nil {
+		if _, err := w.Write([]byte{objNode}); err != nil {
+			return err
+		}
+		return writeNode(w, n)
+	}
+	if p, err := o.Predicate(); err == nil {
+		if _, err := w.Write([]byte{objPredicate}); err != nil {
+			return err
+		}
+		return writePredicate(w, p)
+	}
+	l, err := o.Literal()
+	if err != nil {
+		return fmt.Errorf("codec: binary: object %s boxes neither a node, predicate nor literal", o)
+	}
+	if _, err := w.Write([]byte{objLiteral}); err != nil {
+		return err
+	}
+	return writeLiteral(w, l)
+}
+
+func readObject(r *bufio.Reader, b literal.Builder) (*triple.Object, error) {
+	kind, err := r.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+	switch kind {
+	case objNode:
+		n, err := readNode(r)
+		if err != nil {
+			return nil, err
+		}
+		return triple.NewNodeObject(n), nil
+	case objPredicate:
+		p, err := readPredicate(r)
+		if err != nil {
+			return nil, err
+		}
+		return triple.NewPredicateObject(p), nil
+	case objLiteral:
+		l, err := readLiteral(r, b)
+		if err != nil {
+			return nil, err
+		}
+		return triple.NewLiteralObject(l), nil
+	default:
+		return nil, fmt.Errorf("codec: binary: unknown object tag %d", kind)
+	}
+}
+
+func writeLiteral(w io.Writer, l *literal.Literal) error {
+	switch l.Type() {
+	case literal.Bool:
+		v, err := l.Bool()
+		if err != nil {
+			return err
+		}
+		vb := byte(0)
+		if v {
+			vb = 1
+		}
+		_, err = w.Write([]byte{litBool, vb})
+		return err
+	case literal.Int64:
+		v, err := l.Int64()
+		if err != nil {
+			return err
+		}
+		if _, err := w.Write([]byte{litInt64}); err != nil {
+			return err
+		}
+		var buf [binary.MaxVarintLen64]byte
+		n := binary.PutVarint(buf[:], v)
+		_, err = w.Write(buf[:n])
+		return err
+	case literal.Float64:
+		v, err := l.Float64()
+		if err != nil {
+			return err
+		}
+		if _, err := w.Write([]byte{litFloat64}); err != nil {
+			return err
+		}
+		var buf [8]byte
+		binary.BigEndian.PutUint64(buf[:], math.Float64bits(v))
+		_, err = w.Write(buf[:])
+		return err
+	case literal.Text:
+		v, err := l.Text()
+		if err != nil {
+			return err
+		}
+		if _, err := w.Write([]byte{litText}); err != nil {
+			return err
+		}
+		return writeString(w, v)
+	case literal.Blob:
+		v, err := l.Blob()
+		if err != nil {
+			return err
+		}
+		if _, err := w.Write([]byte{litBlob}); err != nil {
+			return err
+		}
+		return writeString(w, string(v))
+	default:
+		return fmt.Errorf("codec: binary: literal %s has unsupported type", l)
+	}
+}
+
+func readLiteral(r *bufio.Reader, b literal.Builder) (*literal.Literal, error) {
+	tag, err := r.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+	switch tag {
+	case litBool:
+		v, err := r.ReadByte()
+		if err != nil {
+			return nil, err
+		}
+		return b.Parse(fmt.Sprintf("%q^^type:bool", strconv.FormatBool(v == 1)))
+	case litInt64:
+		v, err := binary.ReadVarint(r)
+		if err != nil {
+			return nil, err
+		}
+		return b.Parse(fmt.Sprintf("%q^^type:int64", strconv.FormatInt(v, 10)))
+	case litFloat64:
+		var buf [8]byte
+		if _, err := io.ReadFull(r, buf[:]); err != nil {
+			return nil, err
+		}
+		v := math.Float64frombits(binary.BigEndian.Uint64(buf[:]))
+		return b.Parse(fmt.Sprintf("%q^^type:float64", strconv.FormatFloat(v, 'g', -1, 64)))
+	case litText:
+		v, err := readString(r)
+		if err != nil {
+			return nil, err
+		}
+		return b.Parse(fmt.Sprintf("%q^^type:text", v))
+	case litBlob:
+		v, err := readString(r)
+		if err != nil {
+			return nil, err
+		}
+		return b.Parse(fmt.Sprintf("%q^^type:blob", v))
+	default:
+		return nil, fmt.Errorf("codec: binary: unknown literal tag %d", tag)
+	}
+}
+
+type binaryIterator struct {
+	r *bufio.Reader
+	b literal.Builder
+}
+
+// Next implements TripleIterator.
+func (it *binaryIterator) Next() (*triple.Triple, error) {
+	s, err := readNode(it.r)
+	if err == io.EOF {
+		return nil, io.EOF
+	}
+	if err != nil {
+		return nil, fmt.Errorf("codec: binary: failed to read subject with error %v", err)
+	}
+	p, err := readPredicate(it.r)
+	if err != nil {
+		return nil, fmt.Errorf("codec: binary: failed to read predicate with error %v", err)
+	}
+	o, err := readObject(it.r, it.b)
+	if err != nil {
+		return nil, fmt.Errorf("codec: binary: failed to read object with error %v", err)
+	}
+	return triple.NewTriple(s, p, o)
+}
@@ -0,0 +1,87 @@
+// Copyright 2015 Google Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package codec
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/google/badwolf/triple"
+	"github.com/google/badwolf/triple/literal"
+)
+
+func init() {
+	Register(bwfCodec{})
+}
+
+// bwfCodec is the built-in codec for BadWolf's own line-oriented triple
+// format, the one accepted by triple.ParseTriple and produced by
+// Triple.String.
+type bwfCodec struct{}
+
+// Name implements Codec.
+func (bwfCodec) Name() string { return "bwf" }
+
+// MediaType implements Codec.
+func (bwfCodec) MediaType() string { return "text/vnd.badwolf" }
+
+// Decode implements Codec.
+func (bwfCodec) Decode(r io.Reader, b literal.Builder) TripleIterator {
+	return &bwfIterator{s: bufio.NewScanner(r), b: b}
+}
+
+// Encode implements Codec.
+func (bwfCodec) Encode(w io.Writer, it TripleIterator) error {
+	for {
+		t, err := it.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintln(w, t.String()); err != nil {
+			return err
+		}
+	}
+}
+
+type bwfIterator struct {
+	s *bufio.Scanner
+	b literal.Builder
+	n int
+}
+
+// Next implements TripleIterator.
+func (it *bwfIterator) Next() (*triple.Triple, error) {
+	for it.s.Scan() {
+		it.n++
+		line := strings.TrimSpace(it.s.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		t, err := triple.ParseTriple(line, it.b)
+		if err != nil {
+			return nil, fmt.Errorf("codec: bwf: line %d: %v", it.n, err)
+		}
+		return t, nil
+	}
+	if err := it.s.Err(); err != nil {
+		return nil, err
+	}
+	return nil, io.EOF
+}
@@ -0,0 +1,123 @@
+// Copyright 2015 Google Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package codec provides a pluggable registry of triple serializations.
+// Storage backends and the BQL REPL can pick a Codec by name or negotiate
+// one from an HTTP-style Accept/Content-Type media type, so new formats
+// (RDF/XML, JSON-LD, ...) can be added without touching core packages.
+package codec
+
+import (
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/google/badwolf/triple"
+	"github.com/google/badwolf/triple/literal"
+)
+
+// TripleIterator yields triples one at a time. Next returns io.EOF once
+// exhausted, matching the convention used throughout this package's codecs.
+type TripleIterator interface {
+	Next() (*triple.Triple, error)
+}
+
+// Codec decodes and encodes triples in a particular wire format.
+type Codec interface {
+	// Name uniquely identifies the codec, e.g. "ntriples".
+	Name() string
+	// MediaType returns the MIME type this codec speaks on the wire, e.g.
+	// "application/n-triples".
+	MediaType() string
+	// Decode returns an iterator over the triples encoded in r.
+	Decode(r io.Reader, b literal.Builder) TripleIterator
+	// Encode writes every triple produced by it to w.
+	Encode(w io.Writer, it TripleIterator) error
+}
+
+var (
+	mu      sync.RWMutex
+	byName  = make(map[string]Codec)
+	byMedia = make(map[string]Codec)
+)
+
+// Register makes a Codec available by its name and media type. It panics if
+// either is already registered, mirroring the fail-fast behavior of
+// database/sql's driver registry.
+func Register(c Codec) {
+	mu.Lock()
+	defer mu.Unlock()
+	if _, ok := byName[c.Name()]; ok {
+		panic(fmt.Sprintf("codec: Register called twice for name %q", c.Name()))
+	}
+	if _, ok := byMedia[c.MediaType()]; ok {
+		panic(fmt.Sprintf("codec: Register called twice for media type %q", c.MediaType()))
+	}
+	byName[c.Name()] = c
+	byMedia[c.MediaType()] = c
+}
+
+// ForName returns the codec registered under name.
+func ForName(name string) (Codec, bool) {
+	mu.RLock()
+	defer mu.RUnlock()
+	c, ok := byName[name]
+	return c, ok
+}
+
+// ForMediaType returns the codec registered for the given media type.
+func ForMediaType(mediaType string) (Codec, bool) {
+	mu.RLock()
+	defer mu.RUnlock()
+	c, ok := byMedia[mediaType]
+	return c, ok
+}
+
+// sliceIterator adapts a static slice of triples to the TripleIterator
+// interface, which is convenient when encoding data already held in memory.
+type sliceIterator struct {
+	ts []*triple.Triple
+	i  int
+}
+
+// NewSliceIterator returns a TripleIterator over ts.
+func NewSliceIterator(ts []*triple.Triple) TripleIterator {
+	return &sliceIterator{ts: ts}
+}
+
+// Next implements TripleIterator.
+func (s *sliceIterator) Next() (*triple.Triple, error) {
+	if s.i >= len(s.ts) {
+		return nil, io.EOF
+	}
+	t := s.ts[s.i]
+	s.i++
+	return t, nil
+}
+
+// Collect drains it into a slice, which is convenient when decoding data
+// that will be held in memory rather than streamed further.
+func Collect(it TripleIterator) ([]*triple.Triple, error) {
+	var ts []*triple.Triple
+	for {
+		t, err := it.Next()
+		if err == io.EOF {
+			return ts, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		ts = append(ts, t)
+	}
+}
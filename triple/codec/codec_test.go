@@ -0,0 +1,108 @@
+// Copyright 2015 Google Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package codec
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/google/badwolf/triple"
+	"github.com/google/badwolf/triple/literal"
+)
+
+func mustParseTriples(t *testing.T, lines []string) []*triple.Triple {
+	b := literal.DefaultBuilder()
+	var ts []*triple.Triple
+	for _, l := range lines {
+		tr, err := triple.ParseTriple(l, b)
+		if err != nil {
+			t.Fatalf("triple.ParseTriple(%q) failed with error %v", l, err)
+		}
+		ts = append(ts, tr)
+	}
+	return ts
+}
+
+// TestCodecsRoundTripStringLiteral is a regression test for the encoder gap
+// where ntriplesCodec and turtleCodec (both backed by triple/rdf) could not
+// encode BadWolf's most common literal shape, a plain string.
+func TestCodecsRoundTripStringLiteral(t *testing.T) {
+	lines := []string{`/person<alice>  "name"@[]  "hello world"^^type:text`}
+	in := mustParseTriples(t, lines)
+
+	for _, name := range []string{"bwf", "ntriples", "turtle", "binary"} {
+		name := name
+		t.Run(name, func(t *testing.T) {
+			c, ok := ForName(name)
+			if !ok {
+				t.Fatalf("codec %q is not registered", name)
+			}
+			var buf bytes.Buffer
+			if err := c.Encode(&buf, NewSliceIterator(in)); err != nil {
+				t.Fatalf("%s.Encode failed with error %v", name, err)
+			}
+			out, err := Collect(c.Decode(&buf, literal.DefaultBuilder()))
+			if err != nil {
+				t.Fatalf("%s.Decode failed with error %v", name, err)
+			}
+			if len(out) != len(in) {
+				t.Fatalf("%s round-tripped %d triples, want %d", name, len(out), len(in))
+			}
+		})
+	}
+}
+
+func TestForMediaTypeMatchesRegisteredCodecs(t *testing.T) {
+	for name, media := range map[string]string{
+		"bwf":      "text/vnd.badwolf",
+		"ntriples": "application/n-triples",
+		"turtle":   "text/turtle",
+		"binary":   "application/vnd.badwolf.binary",
+	} {
+		c, ok := ForMediaType(media)
+		if !ok {
+			t.Fatalf("no codec registered for media type %q", media)
+		}
+		if c.Name() != name {
+			t.Errorf("ForMediaType(%q).Name() = %q, want %q", media, c.Name(), name)
+		}
+	}
+}
+
+func TestBinaryCodecRoundTripsAllLiteralTypes(t *testing.T) {
+	lines := []string{
+		`/person<alice>  "age"@[]  "42"^^type:int64`,
+		`/person<alice>  "score"@[]  "1.5"^^type:float64`,
+		`/person<alice>  "active"@[]  "true"^^type:bool`,
+		`/person<alice>  "bio"@[]  "hello"^^type:text`,
+	}
+	in := mustParseTriples(t, lines)
+
+	c, ok := ForName("binary")
+	if !ok {
+		t.Fatal("binary codec is not registered")
+	}
+	var buf bytes.Buffer
+	if err := c.Encode(&buf, NewSliceIterator(in)); err != nil {
+		t.Fatalf("Encode failed with error %v", err)
+	}
+	out, err := Collect(c.Decode(&buf, literal.DefaultBuilder()))
+	if err != nil {
+		t.Fatalf("Decode failed with error %v", err)
+	}
+	if len(out) != len(in) {
+		t.Fatalf("round-tripped %d triples, want %d", len(out), len(in))
+	}
+}
@@ -0,0 +1,91 @@
+// Copyright 2015 Google Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package codec
+
+import (
+	"io"
+
+	"github.com/google/badwolf/triple"
+	"github.com/google/badwolf/triple/literal"
+	"github.com/google/badwolf/triple/rdf"
+)
+
+func init() {
+	Register(ntriplesCodec{})
+	Register(turtleCodec{})
+}
+
+// ntriplesCodec wraps triple/rdf's N-Triples reader and writer.
+type ntriplesCodec struct{}
+
+// Name implements Codec.
+func (ntriplesCodec) Name() string { return "ntriples" }
+
+// MediaType implements Codec.
+func (ntriplesCodec) MediaType() string { return "application/n-triples" }
+
+// Decode implements Codec.
+func (ntriplesCodec) Decode(r io.Reader, b literal.Builder) TripleIterator {
+	return readerFunc(rdf.NewNTriplesReader(r, b).Read)
+}
+
+// Encode implements Codec.
+func (ntriplesCodec) Encode(w io.Writer, it TripleIterator) error {
+	nw := rdf.NewNTriplesWriter(w)
+	return encodeAll(it, nw.Write)
+}
+
+// turtleCodec wraps triple/rdf's Turtle reader and writer.
+type turtleCodec struct{}
+
+// Name implements Codec.
+func (turtleCodec) Name() string { return "turtle" }
+
+// MediaType implements Codec.
+func (turtleCodec) MediaType() string { return "text/turtle" }
+
+// Decode implements Codec.
+func (turtleCodec) Decode(r io.Reader, b literal.Builder) TripleIterator {
+	return readerFunc(rdf.NewTurtleReader(r, b).Read)
+}
+
+// Encode implements Codec.
+func (turtleCodec) Encode(w io.Writer, it TripleIterator) error {
+	tw := rdf.NewTurtleWriter(w, nil)
+	return encodeAll(it, tw.Write)
+}
+
+// readerFunc adapts any "Read() (*triple.Triple, error)" method, such as
+// rdf.NTriplesReader.Read or rdf.TurtleReader.Read, to TripleIterator.
+type readerFunc func() (*triple.Triple, error)
+
+// Next implements TripleIterator.
+func (f readerFunc) Next() (*triple.Triple, error) { return f() }
+
+// encodeAll drains it, handing every triple to write.
+func encodeAll(it TripleIterator, write func(*triple.Triple) error) error {
+	for {
+		t, err := it.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err := write(t); err != nil {
+			return err
+		}
+	}
+}
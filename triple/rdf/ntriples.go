@@ -0,0 +1,219 @@
+// Copyright 2015 Google Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rdf
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/google/badwolf/triple"
+	"github.com/google/badwolf/triple/literal"
+	"github.com/google/badwolf/triple/node"
+	"github.com/google/badwolf/triple/predicate"
+)
+
+// ntLine matches a single W3C N-Triples statement: <s> <p> (<o>|_:o|"lit") .
+var ntLine = regexp.MustCompile(`^\s*(<[^>]*>|_:[^\s]+)\s+(<[^>]*>)\s+(.+?)\s*\.\s*$`)
+
+// ntLiteral matches a literal object, capturing its lexical value, optional
+// language tag and optional datatype IRI.
+var ntLiteral = regexp.MustCompile(`^"((?:[^"\\]|\\.)*)"(?:@([A-Za-z-]+)|\^\^<([^>]*)>)?$`)
+
+// blankNodes hands out a stable BadWolf blank node for every distinct
+// Turtle/N-Triples blank node label seen within a single parse.
+type blankNodes map[string]*node.Node
+
+func (b blankNodes) get(label string) *node.Node {
+	if n, ok := b[label]; ok {
+		return n
+	}
+	n := node.NewBlankNode()
+	b[label] = n
+	return n
+}
+
+// NTriplesReader reads a stream of W3C N-Triples statements and yields the
+// equivalent BadWolf triples.
+type NTriplesReader struct {
+	s      *bufio.Scanner
+	b      literal.Builder
+	blanks blankNodes
+	n      int
+}
+
+// NewNTriplesReader creates a reader that decodes N-Triples from r, building
+// literals with b.
+func NewNTriplesReader(r io.Reader, b literal.Builder) *NTriplesReader {
+	return &NTriplesReader{
+		s:      bufio.NewScanner(r),
+		b:      b,
+		blanks: make(blankNodes),
+	}
+}
+
+// Read returns the next triple in the stream. It returns io.EOF once the
+// underlying reader is exhausted.
+func (r *NTriplesReader) Read() (*triple.Triple, error) {
+	for r.s.Scan() {
+		r.n++
+		line := strings.TrimSpace(r.s.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		m := ntLine.FindStringSubmatch(line)
+		if m == nil {
+			return nil, fmt.Errorf("rdf.NTriplesReader.Read: line %d is not valid N-Triples: %q", r.n, line)
+		}
+		t, err := decodeStatement(m[1], m[2], m[3], r.b, r.blanks)
+		if err != nil {
+			return nil, fmt.Errorf("rdf.NTriplesReader.Read: line %d: %v", r.n, err)
+		}
+		return t, nil
+	}
+	if err := r.s.Err(); err != nil {
+		return nil, fmt.Errorf("rdf.NTriplesReader.Read: failed scanning input with error %v", err)
+	}
+	return nil, io.EOF
+}
+
+// decodeStatement turns the raw subject, predicate and object terms of an
+// N-Triples/Turtle statement into a BadWolf triple.
+func decodeStatement(subject, pred, object string, b literal.Builder, blanks blankNodes) (*triple.Triple, error) {
+	s, err := decodeSubject(subject, blanks)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode subject %q with error %v", subject, err)
+	}
+	p, err := decodePredicate(pred)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode predicate %q with error %v", pred, err)
+	}
+	o, err := decodeObject(object, b, blanks)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode object %q with error %v", object, err)
+	}
+	return triple.NewTriple(s, p, o)
+}
+
+func decodeSubject(term string, blanks blankNodes) (*node.Node, error) {
+	if strings.HasPrefix(term, "_:") {
+		return blanks.get(term), nil
+	}
+	if !strings.HasPrefix(term, "<") || !strings.HasSuffix(term, ">") {
+		return nil, fmt.Errorf("subject %q must be an IRI or a blank node", term)
+	}
+	return iriToNode(term[1 : len(term)-1])
+}
+
+func decodePredicate(term string) (*predicate.Predicate, error) {
+	if !strings.HasPrefix(term, "<") || !strings.HasSuffix(term, ">") {
+		return nil, fmt.Errorf("predicate %q must be an IRI", term)
+	}
+	return predicateFromIRI(term[1 : len(term)-1])
+}
+
+func decodeObject(term string, b literal.Builder, blanks blankNodes) (*triple.Object, error) {
+	if strings.HasPrefix(term, "\"") {
+		m := ntLiteral.FindStringSubmatch(term)
+		if m == nil {
+			return nil, fmt.Errorf("invalid literal term %q", term)
+		}
+		value, lang, datatype := unescapeNTString(m[1]), m[2], m[3]
+		if lang != "" {
+			// BadWolf literals carry no language tag; fold it into the
+			// value so the information is preserved rather than dropped.
+			value, datatype = value+"@"+lang, xsdString
+		}
+		l, err := termToLiteral(value, datatype, b)
+		if err != nil {
+			return nil, err
+		}
+		return triple.NewLiteralObject(l), nil
+	}
+	if strings.HasPrefix(term, "_:") {
+		return triple.NewNodeObject(blanks.get(term)), nil
+	}
+	if !strings.HasPrefix(term, "<") || !strings.HasSuffix(term, ">") {
+		return nil, fmt.Errorf("object %q must be an IRI, a blank node or a literal", term)
+	}
+	n, err := iriToNode(term[1 : len(term)-1])
+	if err != nil {
+		return nil, err
+	}
+	return triple.NewNodeObject(n), nil
+}
+
+func unescapeNTString(s string) string {
+	r := strings.NewReplacer(`\"`, `"`, `\\`, `\`, `\n`, "\n", `\r`, "\r", `\t`, "\t")
+	return r.Replace(s)
+}
+
+// NTriplesWriter serializes BadWolf triples as W3C N-Triples statements.
+type NTriplesWriter struct {
+	w io.Writer
+}
+
+// NewNTriplesWriter creates a writer that encodes triples as N-Triples to w.
+func NewNTriplesWriter(w io.Writer) *NTriplesWriter {
+	return &NTriplesWriter{w: w}
+}
+
+// Write emits t as a single N-Triples statement.
+func (w *NTriplesWriter) Write(t *triple.Triple) error {
+	line, err := encodeStatement(t)
+	if err != nil {
+		return fmt.Errorf("rdf.NTriplesWriter.Write: %v", err)
+	}
+	_, err = fmt.Fprintf(w.w, "%s .\n", line)
+	return err
+}
+
+// encodeStatement renders t's subject, predicate and object as the three
+// space-separated terms shared by N-Triples and (expanded) Turtle.
+func encodeStatement(t *triple.Triple) (string, error) {
+	s, err := encodeNodeTerm(t.S())
+	if err != nil {
+		return "", err
+	}
+	pi, err := predicateToIRI(t.P())
+	if err != nil {
+		return "", err
+	}
+	o, err := encodeObject(t.O())
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s <%s> %s", s, pi, o), nil
+}
+
+func encodeObject(o *triple.Object) (string, error) {
+	if n, err := o.Node(); err == nil {
+		return encodeNodeTerm(n)
+	}
+	if p, err := o.Predicate(); err == nil {
+		iri, err := predicateToIRI(p)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("<%s>", iri), nil
+	}
+	l, err := o.Literal()
+	if err != nil {
+		return "", fmt.Errorf("object %s boxes neither a node, predicate nor literal", o)
+	}
+	return literalToTerm(l)
+}
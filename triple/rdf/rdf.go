@@ -0,0 +1,177 @@
+// Copyright 2015 Google Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package rdf provides streaming readers and writers that translate
+// BadWolf triples to and from the standard W3C N-Triples and Turtle RDF
+// serializations. This allows BadWolf graphs to interoperate with the wider
+// RDF tooling ecosystem without giving up the native ParseTriple format.
+package rdf
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/badwolf/triple/literal"
+	"github.com/google/badwolf/triple/node"
+	"github.com/google/badwolf/triple/predicate"
+)
+
+// xsd datatype IRIs recognized when decoding, and minted when encoding,
+// typed literals.
+const (
+	xsdString  = "xsd:string"
+	xsdInt64   = "xsd:int"
+	xsdFloat64 = "xsd:double"
+	xsdBool    = "xsd:boolean"
+	xsdBase64  = "xsd:base64Binary"
+)
+
+// rfc3339Nano is used to format and parse the time anchors smuggled into the
+// IRIs minted for temporal predicates.
+const rfc3339Nano = "2006-01-02T15:04:05.999999999Z07:00"
+
+// blankNodeType is the node type node.NewBlankNode mints its nodes under.
+// encodeNodeTerm uses it to tell a reified blank node apart from a regular
+// IRI-backed node.
+const blankNodeType = "_"
+
+// nodeTypeAndID splits a node's "/type<id>" string form into its type and id.
+func nodeTypeAndID(n *node.Node) (string, string, error) {
+	s := n.String()
+	open := strings.IndexByte(s, '<')
+	if len(s) < 2 || s[0] != '/' || s[len(s)-1] != '>' || open < 0 {
+		return "", "", fmt.Errorf("rdf: cannot encode node %q as an IRI", s)
+	}
+	return s[1:open], s[open+1 : len(s)-1], nil
+}
+
+// encodeNodeTerm renders n as the term N-Triples and Turtle use for a node:
+// native "_:id" blank node syntax for a node minted by node.NewBlankNode
+// (such as the ones Triple.ReifyWith produces), or a "<type/id>" IRI
+// otherwise.
+func encodeNodeTerm(n *node.Node) (string, error) {
+	typ, id, err := nodeTypeAndID(n)
+	if err != nil {
+		return "", err
+	}
+	if typ == blankNodeType {
+		return "_:" + id, nil
+	}
+	return fmt.Sprintf("<%s/%s>", typ, id), nil
+}
+
+// iriToNode turns an IRI encoded as a "type/id" slug back into the BadWolf
+// node it was generated from.
+func iriToNode(iri string) (*node.Node, error) {
+	idx := strings.IndexByte(iri, '/')
+	if idx < 0 {
+		return nil, fmt.Errorf("rdf: IRI %q is not a valid type/id node slug", iri)
+	}
+	return node.Parse(fmt.Sprintf("/%s<%s>", iri[:idx], iri[idx+1:]))
+}
+
+// predicateToIRI encodes a BadWolf predicate as an RDF IRI. Immutable
+// predicates map to "pred/<id>". Temporal predicates keep their time anchor
+// by appending it after an "@", which predicateFromIRI knows how to reverse.
+func predicateToIRI(p *predicate.Predicate) (string, error) {
+	if p.Type() == predicate.Temporal {
+		ta, err := p.TimeAnchor()
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("pred/%s@%s", string(p.ID()), ta.Format(rfc3339Nano)), nil
+	}
+	return "pred/" + string(p.ID()), nil
+}
+
+// predicateFromIRI reverses predicateToIRI.
+func predicateFromIRI(iri string) (*predicate.Predicate, error) {
+	id := strings.TrimPrefix(iri, "pred/")
+	at := strings.IndexByte(id, '@')
+	if at < 0 {
+		return predicate.NewImmutable(id)
+	}
+	ta, err := time.Parse(rfc3339Nano, id[at+1:])
+	if err != nil {
+		return nil, fmt.Errorf("rdf: invalid time anchor in predicate IRI %q: %v", iri, err)
+	}
+	return predicate.NewTemporal(id[:at], ta)
+}
+
+// literalToTerm renders a BadWolf literal as an RDF typed literal term of
+// the form `"value"^^<xsd:type>`.
+func literalToTerm(l *literal.Literal) (string, error) {
+	switch l.Type() {
+	case literal.Bool:
+		v, err := l.Bool()
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("%q^^<%s>", strconv.FormatBool(v), xsdBool), nil
+	case literal.Int64:
+		v, err := l.Int64()
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("%q^^<%s>", strconv.FormatInt(v, 10), xsdInt64), nil
+	case literal.Float64:
+		v, err := l.Float64()
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("%q^^<%s>", strconv.FormatFloat(v, 'g', -1, 64), xsdFloat64), nil
+	case literal.Text:
+		v, err := l.Text()
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("%q^^<%s>", v, xsdString), nil
+	case literal.Blob:
+		v, err := l.Blob()
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("%q^^<%s>", base64.StdEncoding.EncodeToString(v), xsdBase64), nil
+	default:
+		return "", fmt.Errorf("rdf: literal %s has unsupported type for RDF encoding", l)
+	}
+}
+
+// termToLiteral parses the lexical value of an RDF typed literal according
+// to the given xsd datatype IRI into the matching BadWolf literal type,
+// building it with b from the `"value"^^type:<name>` text Builder.Parse
+// expects. An empty datatype defaults to xsd:string.
+func termToLiteral(value, datatype string, b literal.Builder) (*literal.Literal, error) {
+	switch datatype {
+	case "", xsdString:
+		return b.Parse(fmt.Sprintf("%q^^type:text", value))
+	case xsdInt64:
+		return b.Parse(fmt.Sprintf("%q^^type:int64", value))
+	case xsdFloat64:
+		return b.Parse(fmt.Sprintf("%q^^type:float64", value))
+	case xsdBool:
+		return b.Parse(fmt.Sprintf("%q^^type:bool", value))
+	case xsdBase64:
+		raw, err := base64.StdEncoding.DecodeString(value)
+		if err != nil {
+			return nil, fmt.Errorf("rdf: invalid base64 literal %q: %v", value, err)
+		}
+		return b.Parse(fmt.Sprintf("%q^^type:blob", string(raw)))
+	default:
+		return nil, fmt.Errorf("rdf: unsupported literal datatype %q", datatype)
+	}
+}
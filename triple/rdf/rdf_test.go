@@ -0,0 +1,117 @@
+// Copyright 2015 Google Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rdf
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/google/badwolf/triple"
+	"github.com/google/badwolf/triple/literal"
+	"github.com/google/badwolf/triple/node"
+)
+
+// roundTrip encodes lines of the BadWolf native format into triples, writes
+// them through an N-Triples writer, reads them back through an N-Triples
+// reader, and returns the re-decoded triples.
+func roundTrip(t *testing.T, lines []string) []*triple.Triple {
+	b := literal.DefaultBuilder()
+	var in []*triple.Triple
+	for _, l := range lines {
+		tr, err := triple.ParseTriple(l, b)
+		if err != nil {
+			t.Fatalf("triple.ParseTriple(%q) failed with error %v", l, err)
+		}
+		in = append(in, tr)
+	}
+
+	var buf bytes.Buffer
+	w := NewNTriplesWriter(&buf)
+	for _, tr := range in {
+		if err := w.Write(tr); err != nil {
+			t.Fatalf("NTriplesWriter.Write(%s) failed with error %v", tr, err)
+		}
+	}
+
+	var out []*triple.Triple
+	r := NewNTriplesReader(&buf, b)
+	for {
+		tr, err := r.Read()
+		if err != nil {
+			break
+		}
+		out = append(out, tr)
+	}
+	return out
+}
+
+func TestNTriplesRoundTripStringLiteral(t *testing.T) {
+	lines := []string{`/person<alice>  "name"@[]  "hello world"^^type:text`}
+	out := roundTrip(t, lines)
+	if len(out) != 1 {
+		t.Fatalf("got %d triples, want 1", len(out))
+	}
+	if !strings.Contains(out[0].O().String(), "hello world") {
+		t.Errorf("round-tripped object %s does not contain the original string literal value", out[0].O())
+	}
+}
+
+func TestNTriplesRoundTripBytesLiteral(t *testing.T) {
+	lines := []string{`/person<alice>  "blob"@[]  "raw bytes"^^type:blob`}
+	out := roundTrip(t, lines)
+	if len(out) != 1 {
+		t.Fatalf("got %d triples, want 1", len(out))
+	}
+}
+
+func TestNTriplesRoundTripNumericAndBoolLiterals(t *testing.T) {
+	lines := []string{
+		`/person<alice>  "age"@[]  "42"^^type:int64`,
+		`/person<alice>  "score"@[]  "1.5"^^type:float64`,
+		`/person<alice>  "active"@[]  "true"^^type:bool`,
+	}
+	out := roundTrip(t, lines)
+	if len(out) != len(lines) {
+		t.Fatalf("got %d triples, want %d", len(out), len(lines))
+	}
+}
+
+// TestNTriplesWriterEncodesBlankNodesNatively is a regression test for
+// blank-node subjects and objects (such as the ones Triple.ReifyWith mints)
+// being written as plain "<type/id>" IRIs instead of native N-Triples "_:id"
+// syntax.
+func TestNTriplesWriterEncodesBlankNodesNatively(t *testing.T) {
+	alice, err := node.Parse(`/person<alice>`)
+	if err != nil {
+		t.Fatalf("node.Parse failed with error %v", err)
+	}
+	p, err := predicateFromIRI("pred/knows")
+	if err != nil {
+		t.Fatalf("predicateFromIRI failed with error %v", err)
+	}
+	tr, err := triple.NewTriple(node.NewBlankNode(), p, triple.NewNodeObject(alice))
+	if err != nil {
+		t.Fatalf("triple.NewTriple failed with error %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := NewNTriplesWriter(&buf).Write(tr); err != nil {
+		t.Fatalf("NTriplesWriter.Write failed with error %v", err)
+	}
+	if !strings.HasPrefix(buf.String(), "_:") {
+		t.Errorf("encoded blank-node subject as %q, want it to start with \"_:\"", buf.String())
+	}
+}
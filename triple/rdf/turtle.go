@@ -0,0 +1,216 @@
+// Copyright 2015 Google Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rdf
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/google/badwolf/triple"
+	"github.com/google/badwolf/triple/literal"
+)
+
+// turtlePrefix matches a "@prefix name: <iri> ." directive.
+var turtlePrefix = regexp.MustCompile(`^@prefix\s+([^:\s]*):\s*<([^>]*)>\s*\.\s*$`)
+
+// turtleBase matches a "@base <iri> ." directive.
+var turtleBase = regexp.MustCompile(`^@base\s+<([^>]*)>\s*\.\s*$`)
+
+// turtleStatement matches a single "subject predicate object ." triple
+// statement once prefixed names have already been expanded to full IRIs.
+var turtleStatement = regexp.MustCompile(`^\s*(<[^>]*>|_:[^\s]+)\s+(<[^>]*>)\s+(.+?)\s*\.\s*$`)
+
+// turtlePrefixedName matches a "prefix:local" term so it can be expanded
+// against the set of directives seen so far.
+var turtlePrefixedName = regexp.MustCompile(`^([^:\s<>]*):([^\s<>]*)$`)
+
+// TurtleReader reads a subset of Turtle sufficient to round-trip BadWolf
+// graphs: one triple per statement line, @prefix/@base directives, blank
+// node labels, and typed or language-tagged literals. It does not support
+// the predicate-object-list ";" or object-list "," shorthands.
+type TurtleReader struct {
+	s        *bufio.Scanner
+	b        literal.Builder
+	blanks   blankNodes
+	prefixes map[string]string
+	base     string
+	n        int
+}
+
+// NewTurtleReader creates a reader that decodes Turtle from r, building
+// literals with b.
+func NewTurtleReader(r io.Reader, b literal.Builder) *TurtleReader {
+	return &TurtleReader{
+		s:        bufio.NewScanner(r),
+		b:        b,
+		blanks:   make(blankNodes),
+		prefixes: make(map[string]string),
+	}
+}
+
+// Read returns the next triple in the stream, transparently consuming any
+// @prefix/@base directives along the way. It returns io.EOF once the
+// underlying reader is exhausted.
+func (r *TurtleReader) Read() (*triple.Triple, error) {
+	for r.s.Scan() {
+		r.n++
+		line := strings.TrimSpace(r.s.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if m := turtlePrefix.FindStringSubmatch(line); m != nil {
+			r.prefixes[m[1]] = m[2]
+			continue
+		}
+		if m := turtleBase.FindStringSubmatch(line); m != nil {
+			r.base = m[1]
+			continue
+		}
+		expanded, err := r.expandPrefixedNames(line)
+		if err != nil {
+			return nil, fmt.Errorf("rdf.TurtleReader.Read: line %d: %v", r.n, err)
+		}
+		m := turtleStatement.FindStringSubmatch(expanded)
+		if m == nil {
+			return nil, fmt.Errorf("rdf.TurtleReader.Read: line %d is not a supported Turtle statement: %q", r.n, line)
+		}
+		t, err := decodeStatement(m[1], m[2], m[3], r.b, r.blanks)
+		if err != nil {
+			return nil, fmt.Errorf("rdf.TurtleReader.Read: line %d: %v", r.n, err)
+		}
+		return t, nil
+	}
+	if err := r.s.Err(); err != nil {
+		return nil, fmt.Errorf("rdf.TurtleReader.Read: failed scanning input with error %v", err)
+	}
+	return nil, io.EOF
+}
+
+// expandPrefixedNames rewrites every "prefix:local" token outside of a
+// quoted literal into a full "<iri>" term, and resolves a bare "@base"
+// relative subject/object term if one is present.
+func (r *TurtleReader) expandPrefixedNames(line string) (string, error) {
+	var out strings.Builder
+	inLiteral := false
+	i := 0
+	for i < len(line) {
+		c := line[i]
+		switch {
+		case c == '"':
+			inLiteral = !inLiteral
+			out.WriteByte(c)
+			i++
+		case inLiteral:
+			out.WriteByte(c)
+			i++
+		case c == ' ' || c == '\t':
+			out.WriteByte(c)
+			i++
+		default:
+			j := i
+			for j < len(line) && line[j] != ' ' && line[j] != '\t' {
+				j++
+			}
+			token := line[i:j]
+			expanded, err := r.expandToken(token)
+			if err != nil {
+				return "", err
+			}
+			out.WriteString(expanded)
+			i = j
+		}
+	}
+	return out.String(), nil
+}
+
+func (r *TurtleReader) expandToken(token string) (string, error) {
+	if token == "" || token[0] == '_' || token == "." {
+		return token, nil
+	}
+	if token[0] == '<' {
+		return r.resolveIRIToken(token)
+	}
+	m := turtlePrefixedName.FindStringSubmatch(token)
+	if m == nil {
+		return token, nil
+	}
+	ns, ok := r.prefixes[m[1]]
+	if !ok {
+		return "", fmt.Errorf("undeclared prefix %q in term %q", m[1], token)
+	}
+	return r.resolveIRIToken("<" + ns + m[2] + ">")
+}
+
+// resolveIRIToken resolves a "<iri>" token against the most recently seen
+// @base directive when the IRI is relative, per the standard RFC 3986
+// reference resolution Turtle's @base builds on. Absolute IRIs, and terms
+// with no @base in scope, are returned unchanged.
+func (r *TurtleReader) resolveIRIToken(token string) (string, error) {
+	if r.base == "" || len(token) < 2 {
+		return token, nil
+	}
+	iri := token[1 : len(token)-1]
+	u, err := url.Parse(iri)
+	if err != nil {
+		return "", fmt.Errorf("invalid IRI %q: %v", iri, err)
+	}
+	if u.IsAbs() {
+		return token, nil
+	}
+	base, err := url.Parse(r.base)
+	if err != nil {
+		return "", fmt.Errorf("invalid @base IRI %q: %v", r.base, err)
+	}
+	return "<" + base.ResolveReference(u).String() + ">", nil
+}
+
+// TurtleWriter serializes BadWolf triples as Turtle, emitting any
+// configured @prefix directives once up front and one expanded triple
+// statement per line thereafter.
+type TurtleWriter struct {
+	w          io.Writer
+	prefixes   map[string]string
+	wroteIntro bool
+}
+
+// NewTurtleWriter creates a writer that encodes triples as Turtle to w.
+// The prefixes map is emitted as @prefix directives before the first
+// triple; it may be nil.
+func NewTurtleWriter(w io.Writer, prefixes map[string]string) *TurtleWriter {
+	return &TurtleWriter{w: w, prefixes: prefixes}
+}
+
+// Write emits t as a single Turtle triple statement, writing the @prefix
+// preamble first if this is the first call.
+func (w *TurtleWriter) Write(t *triple.Triple) error {
+	if !w.wroteIntro {
+		for p, ns := range w.prefixes {
+			if _, err := fmt.Fprintf(w.w, "@prefix %s: <%s> .\n", p, ns); err != nil {
+				return err
+			}
+		}
+		w.wroteIntro = true
+	}
+	line, err := encodeStatement(t)
+	if err != nil {
+		return fmt.Errorf("rdf.TurtleWriter.Write: %v", err)
+	}
+	_, err = fmt.Fprintf(w.w, "%s .\n", line)
+	return err
+}
@@ -0,0 +1,58 @@
+// Copyright 2015 Google Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rdf
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/google/badwolf/triple/literal"
+)
+
+// TestTurtleReaderResolvesRelativeIRIsAgainstBase is a regression test for
+// @base being parsed but never applied: a relative IRI term must be resolved
+// against the most recently seen @base directive before decoding.
+func TestTurtleReaderResolvesRelativeIRIsAgainstBase(t *testing.T) {
+	r := NewTurtleReader(strings.NewReader(`@base <http://example.org/> .`), literal.DefaultBuilder())
+	if _, err := r.Read(); err != io.EOF {
+		t.Fatalf("Read of a @base-only stream returned error %v, want io.EOF", err)
+	}
+
+	got, err := r.expandToken("<person/alice>")
+	if err != nil {
+		t.Fatalf("expandToken failed with error %v", err)
+	}
+	if want := "<http://example.org/person/alice>"; got != want {
+		t.Errorf("expandToken resolved relative IRI to %q, want %q", got, want)
+	}
+}
+
+// TestTurtleReaderLeavesAbsoluteIRIsAloneUnderBase checks that an IRI which
+// is already absolute is never rewritten, even once a @base is in scope.
+func TestTurtleReaderLeavesAbsoluteIRIsAloneUnderBase(t *testing.T) {
+	r := NewTurtleReader(strings.NewReader(`@base <http://example.org/> .`), literal.DefaultBuilder())
+	if _, err := r.Read(); err != io.EOF {
+		t.Fatalf("Read of a @base-only stream returned error %v, want io.EOF", err)
+	}
+
+	got, err := r.expandToken("<http://other.org/bob>")
+	if err != nil {
+		t.Fatalf("expandToken failed with error %v", err)
+	}
+	if want := "<http://other.org/bob>"; got != want {
+		t.Errorf("expandToken rewrote absolute IRI to %q, want %q", got, want)
+	}
+}
@@ -199,56 +199,184 @@ func ParseTriple(line string, b literal.Builder) (*Triple, error) {
 	return NewTriple(s, p, o)
 }
 
+// ReificationMode selects the vocabulary ReifyWith uses to describe a
+// reified statement.
+type ReificationMode int8
+
+const (
+	// BadWolfTemporal reifies using BadWolf's own "_subject"/"_predicate"/
+	// "_object" predicates, preserving the original predicate's time anchor
+	// (and identity) on each of them.
+	BadWolfTemporal ReificationMode = iota
+	// RDFStandard reifies using the canonical rdf:type rdf:Statement,
+	// rdf:subject, rdf:predicate and rdf:object predicates so the result
+	// round-trips through standard RDF tooling.
+	RDFStandard
+	// SingletonProperty reifies by minting a predicate IRI unique to this
+	// statement and attaching provenance triples to a node standing for
+	// that predicate instead of to a blank node, following the RDF
+	// singleton property convention.
+	SingletonProperty
+)
+
+// String provides a readable version of the ReificationMode.
+func (m ReificationMode) String() string {
+	switch m {
+	case BadWolfTemporal:
+		return "BADWOLF_TEMPORAL"
+	case RDFStandard:
+		return "RDF_STANDARD"
+	case SingletonProperty:
+		return "SINGLETON_PROPERTY"
+	default:
+		return "UNKNOWN"
+	}
+}
+
 // Reify given the current triple it returns the original triple and the newly
-// reified ones. It also returns the newly created blank node.
+// reified ones using BadWolf's native temporal-anchor-preserving predicates.
+// It also returns the newly created blank node. It is equivalent to calling
+// ReifyWith(BadWolfTemporal).
 func (t *Triple) Reify() ([]*Triple, *node.Node, error) {
-	// Function that create the proper reification predicates.
-	rp := func(id string, p *predicate.Predicate) (*predicate.Predicate, error) {
-		if p.Type() == predicate.Temporal {
-			ta, _ := p.TimeAnchor()
-			return predicate.NewTemporal(string(p.ID()), *ta)
+	return t.ReifyWith(BadWolfTemporal)
+}
+
+// ReifyWith reifies the triple according to mode, returning the original
+// triple together with the newly generated reification triples, and the
+// blank (or singleton-deriving) node minted to describe the statement.
+func (t *Triple) ReifyWith(mode ReificationMode) ([]*Triple, *node.Node, error) {
+	switch mode {
+	case RDFStandard:
+		return t.reifyRDFStandard()
+	case SingletonProperty:
+		return t.reifySingletonProperty()
+	default:
+		return t.reifyBadWolfTemporal()
+	}
+}
+
+// reifyBadWolfTemporal mints "<role>_<original predicate ID>" predicates
+// rooted at a fresh blank node, preserving the original predicate's time
+// anchor when it is temporal so the reification can be temporally queried
+// just like the statement it describes.
+func (t *Triple) reifyBadWolfTemporal() ([]*Triple, *node.Node, error) {
+	rp := func(role string) (*predicate.Predicate, error) {
+		id := fmt.Sprintf("%s_%s", role, string(t.p.ID()))
+		if t.p.Type() == predicate.Temporal {
+			ta, err := t.p.TimeAnchor()
+			if err != nil {
+				return nil, err
+			}
+			return predicate.NewTemporal(id, *ta)
 		}
 		return predicate.NewImmutable(id)
 	}
 
-	fmt.Println(t.String())
 	b := node.NewBlankNode()
-	s, err := rp("_subject", t.p)
+	sp, err := rp("_subject")
 	if err != nil {
 		return nil, nil, err
 	}
-	ts, _ := NewTriple(b, s, NewNodeObject(t.s))
-	p, err := rp("_predicate", t.p)
+	ts, err := NewTriple(b, sp, NewNodeObject(t.s))
 	if err != nil {
 		return nil, nil, err
 	}
-	tp, _ := NewTriple(b, p, NewPredicateObject(t.p))
-	var to *Triple
-	if t.o.l != nil {
-		o, err := rp("_object", t.p)
-		if err != nil {
-			return nil, nil, err
-		}
-		to, _ = NewTriple(b, o, NewLiteralObject(t.o.l))
+	pp, err := rp("_predicate")
+	if err != nil {
+		return nil, nil, err
 	}
-	if t.o.n != nil {
-		o, err := rp("_object", t.p)
-		if err != nil {
-			return nil, nil, err
-		}
-		to, _ = NewTriple(b, o, NewNodeObject(t.o.n))
+	tp, err := NewTriple(b, pp, NewPredicateObject(t.p))
+	if err != nil {
+		return nil, nil, err
 	}
-	if t.o.p != nil {
-		o, err := rp("_object", t.p)
-		if err != nil {
-			return nil, nil, err
-		}
-		to, _ = NewTriple(b, o, NewPredicateObject(t.o.p))
+	op, err := rp("_object")
+	if err != nil {
+		return nil, nil, err
+	}
+	to, err := NewTriple(b, op, t.o)
+	if err != nil {
+		return nil, nil, err
 	}
-
 	return []*Triple{t, ts, tp, to}, b, nil
 }
 
+// reifyRDFStandard describes the triple using the four canonical RDF
+// reification predicates rooted at a fresh blank node.
+func (t *Triple) reifyRDFStandard() ([]*Triple, *node.Node, error) {
+	b := node.NewBlankNode()
+	stmt, err := node.Parse("/rdf<Statement>")
+	if err != nil {
+		return nil, nil, err
+	}
+	rdfType, err := predicate.NewImmutable("rdf:type")
+	if err != nil {
+		return nil, nil, err
+	}
+	tType, err := NewTriple(b, rdfType, NewNodeObject(stmt))
+	if err != nil {
+		return nil, nil, err
+	}
+	rdfSubject, err := predicate.NewImmutable("rdf:subject")
+	if err != nil {
+		return nil, nil, err
+	}
+	tSubject, err := NewTriple(b, rdfSubject, NewNodeObject(t.s))
+	if err != nil {
+		return nil, nil, err
+	}
+	rdfPredicate, err := predicate.NewImmutable("rdf:predicate")
+	if err != nil {
+		return nil, nil, err
+	}
+	tPredicate, err := NewTriple(b, rdfPredicate, NewPredicateObject(t.p))
+	if err != nil {
+		return nil, nil, err
+	}
+	rdfObject, err := predicate.NewImmutable("rdf:object")
+	if err != nil {
+		return nil, nil, err
+	}
+	tObject, err := NewTriple(b, rdfObject, t.o)
+	if err != nil {
+		return nil, nil, err
+	}
+	return []*Triple{t, tType, tSubject, tPredicate, tObject}, b, nil
+}
+
+// reifySingletonProperty mints a predicate IRI unique to this statement,
+// restates the triple using it in place of the original predicate, and
+// attaches an rdf:singletonPropertyOf provenance triple linking it back to
+// the original predicate. Since a BadWolf triple's subject must be a node,
+// the provenance triple is rooted at a node surrogate for the singleton
+// predicate rather than at the predicate itself; that surrogate is the node
+// ReifyWith returns for this mode.
+func (t *Triple) reifySingletonProperty() ([]*Triple, *node.Node, error) {
+	anchor := node.NewBlankNode()
+	slug := strings.NewReplacer("/", "_", "<", "_", ">", "_").Replace(anchor.String())
+	singletonID := fmt.Sprintf("%s#%s", string(t.p.ID()), slug)
+	singleton, err := predicate.NewImmutable(singletonID)
+	if err != nil {
+		return nil, nil, err
+	}
+	ts, err := NewTriple(t.s, singleton, t.o)
+	if err != nil {
+		return nil, nil, err
+	}
+	singletonNode, err := node.Parse(fmt.Sprintf("/pred<%s>", singletonID))
+	if err != nil {
+		return nil, nil, err
+	}
+	singletonOf, err := predicate.NewImmutable("rdf:singletonPropertyOf")
+	if err != nil {
+		return nil, nil, err
+	}
+	tp, err := NewTriple(singletonNode, singletonOf, NewPredicateObject(t.p))
+	if err != nil {
+		return nil, nil, err
+	}
+	return []*Triple{t, ts, tp}, singletonNode, nil
+}
+
 // GUID returns a global unique identifier for the given triple. It is
 // implemented as the base64 encoded stringified version of the triple.
 func (t *Triple) GUID() string {
@@ -0,0 +1,176 @@
+// Copyright 2015 Google Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// This file lives in triple_test rather than triple because it exercises
+// ReifyWith's round trip through triple/rdf, and triple/rdf imports triple.
+package triple_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/google/badwolf/triple"
+	"github.com/google/badwolf/triple/literal"
+	"github.com/google/badwolf/triple/node"
+	"github.com/google/badwolf/triple/predicate"
+	"github.com/google/badwolf/triple/rdf"
+)
+
+// reifyRoundTrip reifies tr under mode, writes every generated triple
+// through an N-Triples writer and reads them back, returning how many were
+// recovered.
+func reifyRoundTrip(t *testing.T, tr *triple.Triple, mode triple.ReificationMode) int {
+	triples, _, err := tr.ReifyWith(mode)
+	if err != nil {
+		t.Fatalf("ReifyWith(%s) failed with error %v", mode, err)
+	}
+
+	b := literal.DefaultBuilder()
+	var buf bytes.Buffer
+	w := rdf.NewNTriplesWriter(&buf)
+	for _, rt := range triples {
+		if err := w.Write(rt); err != nil {
+			t.Fatalf("NTriplesWriter.Write(%s) failed with error %v", rt, err)
+		}
+	}
+
+	n := 0
+	r := rdf.NewNTriplesReader(&buf, b)
+	for {
+		if _, err := r.Read(); err != nil {
+			break
+		}
+		n++
+	}
+	return n
+}
+
+func testTriple(t *testing.T) *triple.Triple {
+	tr, err := triple.ParseTriple(`/person<alice>  "knows"@[]  /person<bob>`, literal.DefaultBuilder())
+	if err != nil {
+		t.Fatalf("ParseTriple failed with error %v", err)
+	}
+	return tr
+}
+
+func TestReifyWithBadWolfTemporal(t *testing.T) {
+	tr := testTriple(t)
+	triples, b, err := tr.ReifyWith(triple.BadWolfTemporal)
+	if err != nil {
+		t.Fatalf("ReifyWith(BadWolfTemporal) failed with error %v", err)
+	}
+	if len(triples) != 4 {
+		t.Fatalf("got %d triples, want 4 (original + subject/predicate/object)", len(triples))
+	}
+	if triples[0] != tr {
+		t.Errorf("first triple should be the original unmodified triple")
+	}
+	if b == nil {
+		t.Errorf("expected a non-nil blank node")
+	}
+	if n := reifyRoundTrip(t, tr, triple.BadWolfTemporal); n != 4 {
+		t.Errorf("round-tripped %d triples through N-Triples, want 4", n)
+	}
+}
+
+func TestReifyWithRDFStandard(t *testing.T) {
+	tr := testTriple(t)
+	triples, _, err := tr.ReifyWith(triple.RDFStandard)
+	if err != nil {
+		t.Fatalf("ReifyWith(RDFStandard) failed with error %v", err)
+	}
+	if len(triples) != 5 {
+		t.Fatalf("got %d triples, want 5 (original + type/subject/predicate/object)", len(triples))
+	}
+	if n := reifyRoundTrip(t, tr, triple.RDFStandard); n != 5 {
+		t.Errorf("round-tripped %d triples through N-Triples, want 5", n)
+	}
+}
+
+func TestReifyWithSingletonProperty(t *testing.T) {
+	tr := testTriple(t)
+	triples, singleton, err := tr.ReifyWith(triple.SingletonProperty)
+	if err != nil {
+		t.Fatalf("ReifyWith(SingletonProperty) failed with error %v", err)
+	}
+	if len(triples) != 3 {
+		t.Fatalf("got %d triples, want 3 (original + singleton statement + provenance)", len(triples))
+	}
+	if singleton == nil {
+		t.Errorf("expected a non-nil singleton node surrogate")
+	}
+	if n := reifyRoundTrip(t, tr, triple.SingletonProperty); n != 3 {
+		t.Errorf("round-tripped %d triples through N-Triples, want 3", n)
+	}
+}
+
+func TestReifyIsEquivalentToReifyWithBadWolfTemporal(t *testing.T) {
+	tr := testTriple(t)
+	viaReify, _, err := tr.Reify()
+	if err != nil {
+		t.Fatalf("Reify failed with error %v", err)
+	}
+	viaReifyWith, _, err := tr.ReifyWith(triple.BadWolfTemporal)
+	if err != nil {
+		t.Fatalf("ReifyWith(BadWolfTemporal) failed with error %v", err)
+	}
+	if len(viaReify) != len(viaReifyWith) {
+		t.Errorf("Reify produced %d triples, ReifyWith(BadWolfTemporal) produced %d", len(viaReify), len(viaReifyWith))
+	}
+}
+
+// TestReifyWithBadWolfTemporalPreservesTimeAnchor exercises the bug the
+// request asked to fix: the predicates minted by ReifyWith(BadWolfTemporal)
+// must stay temporal and keep the original predicate's time anchor, not just
+// fall back to the immutable case exercised by a plain testTriple.
+func TestReifyWithBadWolfTemporalPreservesTimeAnchor(t *testing.T) {
+	s, err := node.Parse(`/person<alice>`)
+	if err != nil {
+		t.Fatalf("node.Parse failed with error %v", err)
+	}
+	o, err := node.Parse(`/person<bob>`)
+	if err != nil {
+		t.Fatalf("node.Parse failed with error %v", err)
+	}
+	anchor := time.Date(2015, 7, 1, 0, 0, 0, 0, time.UTC)
+	p, err := predicate.NewTemporal("knows", anchor)
+	if err != nil {
+		t.Fatalf("predicate.NewTemporal failed with error %v", err)
+	}
+	tr, err := triple.NewTriple(s, p, triple.NewNodeObject(o))
+	if err != nil {
+		t.Fatalf("NewTriple failed with error %v", err)
+	}
+
+	triples, _, err := tr.ReifyWith(triple.BadWolfTemporal)
+	if err != nil {
+		t.Fatalf("ReifyWith(BadWolfTemporal) failed with error %v", err)
+	}
+	if len(triples) != 4 {
+		t.Fatalf("got %d triples, want 4", len(triples))
+	}
+	for _, rt := range triples[1:] {
+		if rt.P().Type() != predicate.Temporal {
+			t.Fatalf("reification predicate %s is not temporal", rt.P())
+		}
+		ta, err := rt.P().TimeAnchor()
+		if err != nil {
+			t.Fatalf("TimeAnchor failed with error %v", err)
+		}
+		if !ta.Equal(anchor) {
+			t.Errorf("reification predicate %s carries time anchor %s, want %s", rt.P(), ta, anchor)
+		}
+	}
+}